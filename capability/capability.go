@@ -0,0 +1,129 @@
+// Package capability aggregates the capabilities reported by every
+// configured data adapter into a single view. The VIS server uses this view
+// to answer W3C VIS getMetadata requests and to reject SetData calls
+// targeting a read-only path before they ever reach a plugin.
+package capability
+
+import (
+	"sync"
+
+	"aos_vis/dataadapter"
+)
+
+/*******************************************************************************
+ * Types
+ ******************************************************************************/
+
+// PathInfo is the aggregated capability view of a single VIS path: which
+// adapter serves it, and the capabilities that adapter reported for it
+type PathInfo struct {
+	Adapter string
+	dataadapter.PathCapabilities
+}
+
+// Registry aggregates dataadapter.AdapterCapabilities across every adapter
+// registered with it into a single, queryable view
+type Registry struct {
+	mutex sync.RWMutex
+
+	adapters map[string]dataadapter.DataAdapter
+	paths    map[string]PathInfo
+}
+
+/*******************************************************************************
+ * Public
+ ******************************************************************************/
+
+// New creates an empty capability registry
+func New() (registry *Registry) {
+	return &Registry{
+		adapters: make(map[string]dataadapter.DataAdapter),
+		paths:    make(map[string]PathInfo),
+	}
+}
+
+// Add registers adapter under name and folds its capabilities into the
+// aggregated view. If more than one registered adapter reports the same
+// path, which of them owns it in the aggregated view is not defined.
+func (registry *Registry) Add(name string, adapter dataadapter.DataAdapter) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	registry.adapters[name] = adapter
+	registry.recomputeLocked()
+}
+
+// Remove unregisters an adapter and recomputes the aggregated view
+func (registry *Registry) Remove(name string) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	delete(registry.adapters, name)
+	registry.recomputeLocked()
+}
+
+// Get returns the adapter registered under name, and whether one is
+// registered at all
+func (registry *Registry) Get(name string) (adapter dataadapter.DataAdapter, ok bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	adapter, ok = registry.adapters[name]
+
+	return adapter, ok
+}
+
+// PathInfo returns the aggregated capability info for path, and whether it
+// is known to any registered adapter
+func (registry *Registry) PathInfo(path string) (info PathInfo, ok bool) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	info, ok = registry.paths[path]
+
+	return info, ok
+}
+
+// IsWritable returns false for a path that is either unknown or reported
+// read-only by its owning adapter. A VIS server dispatcher should call this
+// before forwarding a SetData request to a plugin.
+func (registry *Registry) IsWritable(path string) (writable bool) {
+	info, ok := registry.PathInfo(path)
+
+	return ok && info.Writable
+}
+
+// Metadata returns the full aggregated path capability view, e.g. to answer
+// a W3C VIS getMetadata request
+func (registry *Registry) Metadata() (paths map[string]PathInfo) {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+
+	paths = make(map[string]PathInfo, len(registry.paths))
+
+	for path, info := range registry.paths {
+		paths[path] = info
+	}
+
+	return paths
+}
+
+/*******************************************************************************
+ * Private
+ ******************************************************************************/
+
+// recomputeLocked rebuilds the aggregated path view from every registered
+// adapter. Called with registry.mutex already held for writing.
+func (registry *Registry) recomputeLocked() {
+	paths := make(map[string]PathInfo)
+
+	for name, adapter := range registry.adapters {
+		caps := adapter.Capabilities()
+
+		for path, pathCaps := range caps.Paths {
+			paths[path] = PathInfo{Adapter: name, PathCapabilities: pathCaps}
+		}
+	}
+
+	registry.paths = paths
+}
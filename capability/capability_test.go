@@ -0,0 +1,126 @@
+package capability_test
+
+import (
+	"context"
+	"testing"
+
+	"aos_vis/capability"
+	"aos_vis/dataadapter"
+)
+
+/*******************************************************************************
+ * Tests
+ ******************************************************************************/
+
+func TestAggregatesCapabilitiesAcrossAdapters(t *testing.T) {
+	registry := capability.New()
+
+	registry.Add("sensors", &fakeAdapter{paths: map[string]dataadapter.PathCapabilities{
+		"Signal.Emulator.Speed": {},
+	}})
+	registry.Add("emulator", &fakeAdapter{paths: map[string]dataadapter.PathCapabilities{
+		"Attribute.Emulator.stop": {Writable: true},
+	}})
+
+	if registry.IsWritable("Signal.Emulator.Speed") {
+		t.Error("Signal.Emulator.Speed should be read-only")
+	}
+
+	if !registry.IsWritable("Attribute.Emulator.stop") {
+		t.Error("Attribute.Emulator.stop should be writable")
+	}
+
+	if registry.IsWritable("Signal.Unknown.Path") {
+		t.Error("Unknown path should not be writable")
+	}
+
+	info, ok := registry.PathInfo("Signal.Emulator.Speed")
+	if !ok {
+		t.Fatal("Signal.Emulator.Speed should be known")
+	}
+
+	if info.Adapter != "sensors" {
+		t.Errorf("Wrong owning adapter: %s", info.Adapter)
+	}
+
+	if len(registry.Metadata()) != 2 {
+		t.Errorf("Wrong metadata size: %d", len(registry.Metadata()))
+	}
+}
+
+func TestRemoveDropsAdapterPaths(t *testing.T) {
+	registry := capability.New()
+
+	registry.Add("sensors", &fakeAdapter{paths: map[string]dataadapter.PathCapabilities{
+		"Signal.Emulator.Speed": {},
+	}})
+
+	registry.Remove("sensors")
+
+	if _, ok := registry.PathInfo("Signal.Emulator.Speed"); ok {
+		t.Error("Path should be gone after its adapter is removed")
+	}
+}
+
+/*******************************************************************************
+ * Types
+ ******************************************************************************/
+
+// fakeAdapter is a minimal dataadapter.DataAdapter used to exercise the
+// capability package without depending on a real plugin
+type fakeAdapter struct {
+	paths  map[string]dataadapter.PathCapabilities
+	closed bool
+}
+
+/*******************************************************************************
+ * dataadapter.DataAdapter
+ ******************************************************************************/
+
+func (adapter *fakeAdapter) GetName() (name string) { return "fake" }
+
+func (adapter *fakeAdapter) GetPathList() (pathList []string, err error) {
+	for path := range adapter.paths {
+		pathList = append(pathList, path)
+	}
+
+	return pathList, nil
+}
+
+func (adapter *fakeAdapter) IsPathPublic(path string) (result bool, err error) { return true, nil }
+
+func (adapter *fakeAdapter) GetData(
+	ctx context.Context, pathList []string) (data map[string]interface{}, err error) {
+	return nil, nil
+}
+
+func (adapter *fakeAdapter) SetData(ctx context.Context, data map[string]interface{}) (err error) {
+	return nil
+}
+
+func (adapter *fakeAdapter) GetSubscribeChannel() (channel <-chan map[string]interface{}) {
+	return nil
+}
+
+func (adapter *fakeAdapter) Subscribe(ctx context.Context, pathList []string) (err error) { return nil }
+
+func (adapter *fakeAdapter) SubscribeFiltered(pathList []string, expr string) (
+	subscriptionID string, err error) {
+	return "", nil
+}
+
+func (adapter *fakeAdapter) Unsubscribe(pathList []string) (err error) { return nil }
+
+func (adapter *fakeAdapter) UnsubscribeID(subscriptionID string) (err error) { return nil }
+
+func (adapter *fakeAdapter) UnsubscribeAll() (err error) { return nil }
+
+func (adapter *fakeAdapter) Capabilities() (capabilities dataadapter.AdapterCapabilities) {
+	return dataadapter.AdapterCapabilities{SupportsSubscribe: true, Paths: adapter.paths}
+}
+
+func (adapter *fakeAdapter) Close() (err error) {
+	adapter.closed = true
+
+	return nil
+}
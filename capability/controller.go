@@ -0,0 +1,219 @@
+package capability
+
+import (
+	"os"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"aos_vis/config"
+	"aos_vis/dataadapter"
+)
+
+/*******************************************************************************
+ * Consts
+ ******************************************************************************/
+
+const defaultPollPeriod = 5 * time.Second
+
+/*******************************************************************************
+ * Types
+ ******************************************************************************/
+
+// Factory creates the adapter instance for a configured plugin
+type Factory func(adapterConfig config.AdapterConfig) (adapter dataadapter.DataAdapter, err error)
+
+// Controller watches a VIS config file and keeps a Registry in sync with the
+// adapters it declares, instantiating newly-configured plugins, disposing of
+// disabled or removed ones, and recomputing the aggregated capability view
+// on every change.
+type Controller struct {
+	configPath string
+	factories  map[string]Factory
+	pollPeriod time.Duration
+
+	registry *Registry
+
+	mutex sync.Mutex
+	stop  chan struct{}
+}
+
+/*******************************************************************************
+ * Public
+ ******************************************************************************/
+
+// NewController creates a controller that loads configPath and keeps
+// registry in sync with it once Start is called. factories maps each
+// config.AdapterConfig.Plugin name to the function that creates its adapter.
+func NewController(configPath string, factories map[string]Factory) (controller *Controller) {
+	return &Controller{
+		configPath: configPath,
+		factories:  factories,
+		pollPeriod: defaultPollPeriod,
+		registry:   New(),
+	}
+}
+
+// Registry returns the controller's live capability registry
+func (controller *Controller) Registry() (registry *Registry) {
+	return controller.registry
+}
+
+// Start loads the config immediately and then watches configPath every poll
+// period, adding, removing and recomputing capabilities as the configured
+// adapter list changes
+func (controller *Controller) Start() (err error) {
+	if err = controller.Reload(); err != nil {
+		return err
+	}
+
+	controller.mutex.Lock()
+	stop := make(chan struct{})
+	controller.stop = stop
+	controller.mutex.Unlock()
+
+	go controller.watch(stop)
+
+	return nil
+}
+
+// Reload immediately re-reads the config file and syncs the registry with
+// it. The watcher started by Start calls this on a poll tick only when the
+// config file's modification time has advanced; call it directly to force an
+// out-of-band refresh without waiting for the next poll.
+func (controller *Controller) Reload() (err error) {
+	return controller.reload()
+}
+
+// Stop stops watching the config file
+func (controller *Controller) Stop() {
+	controller.mutex.Lock()
+	defer controller.mutex.Unlock()
+
+	if controller.stop != nil {
+		close(controller.stop)
+		controller.stop = nil
+	}
+}
+
+/*******************************************************************************
+ * Private
+ ******************************************************************************/
+
+// watch polls configPath every pollPeriod until stop is closed. stop is
+// passed in rather than read from controller.stop on each iteration, since
+// that field is written under controller.mutex by Stop and reading it here
+// without the same lock would race.
+func (controller *Controller) watch(stop <-chan struct{}) {
+	ticker := time.NewTicker(controller.pollPeriod)
+	defer ticker.Stop()
+
+	lastModTime := controller.configModTime()
+
+	for {
+		select {
+		case <-stop:
+			return
+
+		case <-ticker.C:
+			modTime := controller.configModTime()
+			if !modTime.After(lastModTime) {
+				continue
+			}
+
+			lastModTime = modTime
+
+			if err := controller.Reload(); err != nil {
+				log.Errorf("Can't reload adapter config: %s", err)
+			}
+		}
+	}
+}
+
+func (controller *Controller) configModTime() (modTime time.Time) {
+	info, err := os.Stat(controller.configPath)
+	if err != nil {
+		log.Errorf("Can't stat config file: %s", err)
+		return modTime
+	}
+
+	return info.ModTime()
+}
+
+// reload reads the config file and brings the registry's adapters in line
+// with the plugins it declares: adapters no longer listed (or disabled) are
+// removed, newly listed ones are created via their factory and added.
+func (controller *Controller) reload() (err error) {
+	cfg, err := config.New(controller.configPath)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]config.AdapterConfig, len(cfg.Adapters))
+
+	for _, adapterConfig := range cfg.Adapters {
+		if adapterConfig.Disabled {
+			continue
+		}
+
+		wanted[adapterConfig.Plugin] = adapterConfig
+	}
+
+	for _, name := range controller.registeredNames() {
+		if _, ok := wanted[name]; !ok {
+			log.Infof("Removing adapter %s", name)
+
+			if adapter, ok := controller.registry.Get(name); ok {
+				if err := adapter.Close(); err != nil {
+					log.Errorf("Can't close adapter %s: %s", name, err)
+				}
+			}
+
+			controller.registry.Remove(name)
+		}
+	}
+
+	for name, adapterConfig := range wanted {
+		if controller.isRegistered(name) {
+			continue
+		}
+
+		factory, ok := controller.factories[name]
+		if !ok {
+			log.Warnf("No factory registered for adapter %s, skipping", name)
+			continue
+		}
+
+		adapter, err := factory(adapterConfig)
+		if err != nil {
+			log.Errorf("Can't create adapter %s: %s", name, err)
+			continue
+		}
+
+		log.Infof("Adding adapter %s", name)
+		controller.registry.Add(name, adapter)
+	}
+
+	return nil
+}
+
+func (controller *Controller) registeredNames() (names []string) {
+	controller.registry.mutex.RLock()
+	defer controller.registry.mutex.RUnlock()
+
+	for name := range controller.registry.adapters {
+		names = append(names, name)
+	}
+
+	return names
+}
+
+func (controller *Controller) isRegistered(name string) (registered bool) {
+	controller.registry.mutex.RLock()
+	defer controller.registry.mutex.RUnlock()
+
+	_, registered = controller.registry.adapters[name]
+
+	return registered
+}
@@ -0,0 +1,103 @@
+package capability_test
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	"aos_vis/capability"
+	"aos_vis/config"
+	"aos_vis/dataadapter"
+)
+
+/*******************************************************************************
+ * Tests
+ ******************************************************************************/
+
+func TestControllerAddsAndRemovesAdaptersOnReload(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configPath := path.Join(tmpDir, "vis.json")
+
+	writeConfig(t, configPath, config.Config{
+		Adapters: []config.AdapterConfig{{Plugin: "sensors"}},
+	})
+
+	adapter := &fakeAdapter{paths: map[string]dataadapter.PathCapabilities{
+		"Signal.Emulator.Speed": {},
+	}}
+
+	factories := map[string]capability.Factory{
+		"sensors": func(config.AdapterConfig) (dataadapter.DataAdapter, error) {
+			return adapter, nil
+		},
+	}
+
+	controller := capability.NewController(configPath, factories)
+
+	if err := controller.Reload(); err != nil {
+		t.Fatalf("Can't reload: %s", err)
+	}
+
+	if _, ok := controller.Registry().PathInfo("Signal.Emulator.Speed"); !ok {
+		t.Fatal("Expected sensors adapter's path to be registered")
+	}
+
+	writeConfig(t, configPath, config.Config{
+		Adapters: []config.AdapterConfig{{Plugin: "sensors", Disabled: true}},
+	})
+
+	if err := controller.Reload(); err != nil {
+		t.Fatalf("Can't reload: %s", err)
+	}
+
+	if _, ok := controller.Registry().PathInfo("Signal.Emulator.Speed"); ok {
+		t.Error("Expected sensors adapter to be removed once disabled")
+	}
+
+	if !adapter.closed {
+		t.Error("Expected sensors adapter to be closed once removed, to avoid leaking its background goroutine")
+	}
+}
+
+// TestStartStopDoesNotRace is a regression test for a data race between
+// watch() reading controller.stop and Stop() writing it: run under
+// `go test -race` it used to fail even though this never waits for a poll
+// tick to fire.
+func TestStartStopDoesNotRace(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	configPath := path.Join(tmpDir, "vis.json")
+
+	writeConfig(t, configPath, config.Config{})
+
+	controller := capability.NewController(configPath, map[string]capability.Factory{})
+
+	if err := controller.Start(); err != nil {
+		t.Fatalf("Can't start: %s", err)
+	}
+
+	controller.Stop()
+
+	// Stop must be idempotent: a second call must not panic on a nil or
+	// already-closed channel
+	controller.Stop()
+}
+
+/*******************************************************************************
+ * Private
+ ******************************************************************************/
+
+func writeConfig(t *testing.T, configPath string, cfg config.Config) {
+	t.Helper()
+
+	data, err := json.Marshal(&cfg)
+	if err != nil {
+		t.Fatalf("Can't marshal config: %s", err)
+	}
+
+	if err = os.WriteFile(configPath, data, 0o644); err != nil {
+		t.Fatalf("Can't write config: %s", err)
+	}
+}
@@ -0,0 +1,88 @@
+package dataadapter
+
+import "context"
+
+/*******************************************************************************
+ * Types
+ ******************************************************************************/
+
+// DataAdapter interface implemented by all VIS data adapter plugins
+type DataAdapter interface {
+	// GetName returns adapter name
+	GetName() (name string)
+
+	// GetPathList returns list of all pathes for this adapter
+	GetPathList() (pathList []string, err error)
+
+	// IsPathPublic returns true if requested data accessible without authorization
+	IsPathPublic(path string) (result bool, err error)
+
+	// GetData returns data by path. ctx bounds how long the call may block,
+	// e.g. on a slow sensor backend.
+	GetData(ctx context.Context, pathList []string) (data map[string]interface{}, err error)
+
+	// SetData sets data by pathes. ctx bounds how long the call may block.
+	SetData(ctx context.Context, data map[string]interface{}) (err error)
+
+	// GetSubscribeChannel returns channel on which data changes will be sent
+	GetSubscribeChannel() (channel <-chan map[string]interface{})
+
+	// Subscribe subscribes for data changes
+	Subscribe(ctx context.Context, pathList []string) (err error)
+
+	// SubscribeFiltered subscribes for data changes matching the given filter
+	// expression, forwarding only events for which it evaluates to true
+	SubscribeFiltered(pathList []string, expr string) (subscriptionID string, err error)
+
+	// Unsubscribe removes every subscription - filtered or not - registered
+	// on exactly pathList
+	Unsubscribe(pathList []string) (err error)
+
+	// UnsubscribeID removes the single subscription identified by
+	// subscriptionID, e.g. one returned by SubscribeFiltered, without
+	// touching any other subscription sharing the same path list
+	UnsubscribeID(subscriptionID string) (err error)
+
+	// UnsubscribeAll unsubscribes from all data changes
+	UnsubscribeAll() (err error)
+
+	// Capabilities returns what this adapter supports (subscribe, filter,
+	// batch size) and, per path, whether it is writable, its numeric range,
+	// unit and access-control class. The VIS server uses this to answer
+	// getMetadata requests and to reject writes to read-only paths before
+	// they reach the plugin.
+	Capabilities() (capabilities AdapterCapabilities)
+
+	// Close stops any background goroutine the adapter started (pollers,
+	// push-transport connections) and releases its resources. The VIS server
+	// calls this when an adapter is disabled or removed so it doesn't leak.
+	Close() (err error)
+}
+
+// PathCapabilities describes a single VIS path as exposed by an adapter
+type PathCapabilities struct {
+	// Writable is true if the path accepts SetData from VIS clients
+	Writable bool
+	// Unit is the path's physical unit, e.g. "celsius", empty if not applicable
+	Unit string `json:",omitempty"`
+	// Min and Max bound a numeric path's value; nil if unbounded or non-numeric
+	Min, Max *float64 `json:",omitempty"`
+	// AccessClass is the access-control class required to read the path,
+	// e.g. "public", "authorized"
+	AccessClass string `json:",omitempty"`
+}
+
+// AdapterCapabilities describes what an adapter supports, so the VIS server
+// can answer W3C VIS getMetadata requests and enforce access rules without
+// calling into the plugin
+type AdapterCapabilities struct {
+	// SupportsSubscribe is true if the adapter honors Subscribe
+	SupportsSubscribe bool
+	// SupportsFilter is true if the adapter honors SubscribeFiltered
+	SupportsFilter bool
+	// BatchSize caps the number of paths accepted per GetData/SetData call,
+	// 0 means unlimited
+	BatchSize int
+	// Paths maps every path served by this adapter to its capabilities
+	Paths map[string]PathCapabilities
+}
@@ -0,0 +1,341 @@
+package dataadapter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"aos_vis/filter"
+)
+
+/*******************************************************************************
+ * Consts
+ ******************************************************************************/
+
+const subscribeChannelSize = 64
+
+/*******************************************************************************
+ * Vars
+ ******************************************************************************/
+
+// ErrDeadlineExceeded is returned by getData/setData when the adapter's read
+// or write deadline elapses before the operation completes
+var ErrDeadlineExceeded = errors.New("dataadapter: deadline exceeded")
+
+/*******************************************************************************
+ * Types
+ ******************************************************************************/
+
+// baseData single path value storage
+type baseData struct {
+	Value interface{}
+}
+
+// subscription registered subscriber: a path list with an optional filter
+// expression. Subscriptions created via subscribe() have a nil filter and
+// match unconditionally.
+type subscription struct {
+	pathList []string
+	filter   *filter.Filter
+}
+
+// BaseAdapter base adapter implementation used by plugin adapters to store
+// data and dispatch subscription events
+type BaseAdapter struct {
+	name string
+
+	data map[string]*baseData
+
+	subscriptions      map[string]*subscription
+	lastSubscriptionID uint64
+	subscribeChannel   chan map[string]interface{}
+
+	readDeadline  deadlineTimer
+	writeDeadline deadlineTimer
+
+	mutex sync.Mutex
+}
+
+/*******************************************************************************
+ * Public
+ ******************************************************************************/
+
+// newBaseAdapter creates new base adapter
+func newBaseAdapter() (adapter *BaseAdapter, err error) {
+	adapter = &BaseAdapter{
+		data:             make(map[string]*baseData),
+		subscriptions:    make(map[string]*subscription),
+		subscribeChannel: make(chan map[string]interface{}, subscribeChannelSize),
+		readDeadline:     makeDeadlineTimer(),
+		writeDeadline:    makeDeadlineTimer(),
+	}
+
+	return adapter, nil
+}
+
+// SetReadDeadline bounds how long subsequent getData calls may block. A zero
+// time.Time clears the deadline.
+func (adapter *BaseAdapter) SetReadDeadline(t time.Time) {
+	adapter.readDeadline.set(t)
+}
+
+// SetWriteDeadline bounds how long subsequent setData calls may block. A zero
+// time.Time clears the deadline.
+func (adapter *BaseAdapter) SetWriteDeadline(t time.Time) {
+	adapter.writeDeadline.set(t)
+}
+
+// capabilities returns the default capability view: every stored path,
+// read-only, plus subscribe and filter support, which every BaseAdapter-backed
+// plugin provides. Plugins that expose writable or bounded paths override the
+// per-path entries on top of this.
+func (adapter *BaseAdapter) capabilities() (capabilities AdapterCapabilities) {
+	adapter.mutex.Lock()
+	defer adapter.mutex.Unlock()
+
+	paths := make(map[string]PathCapabilities, len(adapter.data))
+
+	for path := range adapter.data {
+		paths[path] = PathCapabilities{}
+	}
+
+	return AdapterCapabilities{
+		SupportsSubscribe: true,
+		SupportsFilter:    true,
+		Paths:             paths,
+	}
+}
+
+func (adapter *BaseAdapter) getName() (name string) {
+	return adapter.name
+}
+
+func (adapter *BaseAdapter) getPathList() (pathList []string, err error) {
+	adapter.mutex.Lock()
+	defer adapter.mutex.Unlock()
+
+	for path := range adapter.data {
+		pathList = append(pathList, path)
+	}
+
+	return pathList, nil
+}
+
+func (adapter *BaseAdapter) getData(
+	ctx context.Context, pathList []string) (data map[string]interface{}, err error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-adapter.readDeadline.channel():
+		return nil, ErrDeadlineExceeded
+	default:
+	}
+
+	adapter.mutex.Lock()
+	defer adapter.mutex.Unlock()
+
+	data = make(map[string]interface{})
+
+	for _, path := range pathList {
+		item, ok := adapter.data[path]
+		if !ok {
+			return nil, fmt.Errorf("path %s doesn't exist", path)
+		}
+
+		data[path] = item.Value
+	}
+
+	return data, nil
+}
+
+func (adapter *BaseAdapter) setData(ctx context.Context, newData map[string]interface{}) (err error) {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-adapter.writeDeadline.channel():
+		return ErrDeadlineExceeded
+	default:
+	}
+
+	adapter.mutex.Lock()
+
+	changes := make(map[string]interface{})
+
+	for path, value := range newData {
+		item, ok := adapter.data[path]
+		if !ok {
+			item = &baseData{}
+			adapter.data[path] = item
+		}
+
+		if item.Value != value {
+			item.Value = value
+			changes[path] = value
+		}
+	}
+
+	messages := adapter.buildNotifications(changes)
+
+	adapter.mutex.Unlock()
+
+	return adapter.dispatchNotifications(ctx, messages)
+}
+
+// subscribe subscribes for data changes on the given paths unconditionally
+func (adapter *BaseAdapter) subscribe(ctx context.Context, pathList []string) (err error) {
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	_, err = adapter.addSubscription(pathList, nil)
+
+	return err
+}
+
+// subscribeFiltered subscribes for data changes on the given paths, forwarding
+// only the changes for which expr evaluates to true. The filter is parsed and
+// validated before the subscription is registered. The returned
+// subscriptionID can be passed to unsubscribeID to remove just this
+// subscription, leaving any other subscription on the same paths intact.
+func (adapter *BaseAdapter) subscribeFiltered(pathList []string, expr string) (subscriptionID string, err error) {
+	parsedFilter, err := filter.New(expr)
+	if err != nil {
+		return "", err
+	}
+
+	return adapter.addSubscription(pathList, parsedFilter)
+}
+
+// unsubscribe removes every subscription - filtered or not - registered on
+// exactly pathList
+func (adapter *BaseAdapter) unsubscribe(pathList []string) (err error) {
+	adapter.mutex.Lock()
+	defer adapter.mutex.Unlock()
+
+	for id, sub := range adapter.subscriptions {
+		if equalPathLists(sub.pathList, pathList) {
+			delete(adapter.subscriptions, id)
+		}
+	}
+
+	return nil
+}
+
+// unsubscribeID removes the single subscription identified by
+// subscriptionID, e.g. one returned by subscribeFiltered, without touching
+// any other subscription sharing the same path list
+func (adapter *BaseAdapter) unsubscribeID(subscriptionID string) (err error) {
+	adapter.mutex.Lock()
+	defer adapter.mutex.Unlock()
+
+	delete(adapter.subscriptions, subscriptionID)
+
+	return nil
+}
+
+func (adapter *BaseAdapter) unsubscribeAll() (err error) {
+	adapter.mutex.Lock()
+	defer adapter.mutex.Unlock()
+
+	adapter.subscriptions = make(map[string]*subscription)
+
+	return nil
+}
+
+/*******************************************************************************
+ * Private
+ ******************************************************************************/
+
+func (adapter *BaseAdapter) addSubscription(
+	pathList []string, parsedFilter *filter.Filter) (subscriptionID string, err error) {
+	adapter.mutex.Lock()
+	defer adapter.mutex.Unlock()
+
+	for _, path := range pathList {
+		if _, ok := adapter.data[path]; !ok {
+			return "", fmt.Errorf("path %s doesn't exist", path)
+		}
+	}
+
+	adapter.lastSubscriptionID++
+	subscriptionID = strconv.FormatUint(adapter.lastSubscriptionID, 10)
+
+	adapter.subscriptions[subscriptionID] = &subscription{pathList: pathList, filter: parsedFilter}
+
+	return subscriptionID, nil
+}
+
+// buildNotifications computes the per-subscription payloads that changes
+// should produce. It is called with adapter.mutex already held, and returns
+// before any blocking send is attempted so the mutex can be released first.
+func (adapter *BaseAdapter) buildNotifications(changes map[string]interface{}) (messages []map[string]interface{}) {
+	if len(adapter.subscriptions) == 0 {
+		return nil
+	}
+
+	snapshot := make(map[string]interface{}, len(adapter.data))
+	for path, item := range adapter.data {
+		snapshot[path] = item.Value
+	}
+
+	for _, sub := range adapter.subscriptions {
+		matched := make(map[string]interface{})
+
+		for _, path := range sub.pathList {
+			if value, ok := changes[path]; ok {
+				matched[path] = value
+			}
+		}
+
+		if len(matched) == 0 {
+			continue
+		}
+
+		if sub.filter != nil && !sub.filter.Match(snapshot) {
+			continue
+		}
+
+		messages = append(messages, matched)
+	}
+
+	return messages
+}
+
+// dispatchNotifications sends each message on subscribeChannel. It must be
+// called without adapter.mutex held: a slow subscriber leaving the channel
+// full would otherwise block every other adapter call behind that lock.
+func (adapter *BaseAdapter) dispatchNotifications(ctx context.Context, messages []map[string]interface{}) (err error) {
+	for _, message := range messages {
+		select {
+		case adapter.subscribeChannel <- message:
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-adapter.writeDeadline.channel():
+			return ErrDeadlineExceeded
+		}
+	}
+
+	return nil
+}
+
+func equalPathLists(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	set := make(map[string]bool, len(a))
+	for _, path := range a {
+		set[path] = true
+	}
+
+	for _, path := range b {
+		if !set[path] {
+			return false
+		}
+	}
+
+	return true
+}
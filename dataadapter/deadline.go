@@ -0,0 +1,62 @@
+package dataadapter
+
+import (
+	"sync"
+	"time"
+)
+
+/*******************************************************************************
+ * Types
+ ******************************************************************************/
+
+// deadlineTimer bounds how long a blocking operation is allowed to wait. It
+// mirrors the net package's deadline timer: the cancel channel is closed by a
+// time.AfterFunc once the deadline elapses, and is replaced by a fresh one
+// whenever the deadline is changed or cleared so a past expiry can't leak
+// into the next deadline.
+type deadlineTimer struct {
+	mutex  sync.Mutex
+	timer  *time.Timer
+	cancel chan struct{}
+}
+
+/*******************************************************************************
+ * Private
+ ******************************************************************************/
+
+func makeDeadlineTimer() deadlineTimer {
+	return deadlineTimer{cancel: make(chan struct{})}
+}
+
+// set arms the deadline, or clears it for a zero time.Time
+func (deadline *deadlineTimer) set(t time.Time) {
+	deadline.mutex.Lock()
+	defer deadline.mutex.Unlock()
+
+	if deadline.timer != nil {
+		deadline.timer.Stop()
+	}
+
+	deadline.cancel = make(chan struct{})
+
+	if t.IsZero() {
+		return
+	}
+
+	duration := time.Until(t)
+	if duration <= 0 {
+		close(deadline.cancel)
+		return
+	}
+
+	cancel := deadline.cancel
+	deadline.timer = time.AfterFunc(duration, func() { close(cancel) })
+}
+
+// channel returns the channel that is closed once the deadline elapses
+func (deadline *deadlineTimer) channel() <-chan struct{} {
+	deadline.mutex.Lock()
+	defer deadline.mutex.Unlock()
+
+	return deadline.cancel
+}
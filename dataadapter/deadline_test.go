@@ -0,0 +1,145 @@
+package dataadapter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+/*******************************************************************************
+ * Tests
+ ******************************************************************************/
+
+func TestDeadlineTimerExpires(t *testing.T) {
+	deadline := makeDeadlineTimer()
+	deadline.set(time.Now().Add(20 * time.Millisecond))
+
+	select {
+	case <-deadline.channel():
+		t.Fatal("Deadline fired before it elapsed")
+	default:
+	}
+
+	select {
+	case <-deadline.channel():
+	case <-time.After(time.Second):
+		t.Fatal("Deadline did not fire after it elapsed")
+	}
+}
+
+func TestDeadlineTimerClear(t *testing.T) {
+	deadline := makeDeadlineTimer()
+	deadline.set(time.Now().Add(20 * time.Millisecond))
+	deadline.set(time.Time{})
+
+	select {
+	case <-deadline.channel():
+		t.Fatal("Cleared deadline must not fire")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestGetDataRespectsReadDeadline(t *testing.T) {
+	adapter, err := newBaseAdapter()
+	if err != nil {
+		t.Fatalf("Can't create adapter: %s", err)
+	}
+
+	adapter.data["Signal.Test.value"] = &baseData{Value: 1}
+
+	adapter.SetReadDeadline(time.Now().Add(-time.Second))
+
+	if _, err = adapter.getData(context.Background(), []string{"Signal.Test.value"}); !errors.Is(err, ErrDeadlineExceeded) {
+		t.Errorf("Expected ErrDeadlineExceeded, got %v", err)
+	}
+}
+
+func TestSetDataRespectsWriteDeadline(t *testing.T) {
+	adapter, err := newBaseAdapter()
+	if err != nil {
+		t.Fatalf("Can't create adapter: %s", err)
+	}
+
+	adapter.SetWriteDeadline(time.Now().Add(-time.Second))
+
+	if err = adapter.setData(context.Background(), map[string]interface{}{"Signal.Test.value": 1}); !errors.Is(err, ErrDeadlineExceeded) {
+		t.Errorf("Expected ErrDeadlineExceeded, got %v", err)
+	}
+}
+
+func TestGetDataRespectsContextCancellation(t *testing.T) {
+	adapter, err := newBaseAdapter()
+	if err != nil {
+		t.Fatalf("Can't create adapter: %s", err)
+	}
+
+	adapter.data["Signal.Test.value"] = &baseData{Value: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err = adapter.getData(ctx, []string{"Signal.Test.value"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+// TestSetDataDoesNotBlockOtherCallsWhenSubscriberChannelIsFull is a regression
+// test: setData must release adapter.mutex before it blocks sending to a
+// full subscribeChannel, so a slow subscriber can't wedge every other call
+// on the adapter behind that lock.
+func TestSetDataDoesNotBlockOtherCallsWhenSubscriberChannelIsFull(t *testing.T) {
+	adapter, err := newBaseAdapter()
+	if err != nil {
+		t.Fatalf("Can't create adapter: %s", err)
+	}
+
+	adapter.data["Signal.Test.value"] = &baseData{Value: 0}
+
+	if _, err = adapter.addSubscription([]string{"Signal.Test.value"}, nil); err != nil {
+		t.Fatalf("Can't subscribe: %s", err)
+	}
+
+	ctx := context.Background()
+
+	// fill subscribeChannel without anyone draining it
+	for i := 0; i < subscribeChannelSize; i++ {
+		if err = adapter.setData(ctx, map[string]interface{}{"Signal.Test.value": i + 1}); err != nil {
+			t.Fatalf("Can't fill subscribe channel: %s", err)
+		}
+	}
+
+	// give the next setData call just long enough to reach and block on the
+	// full channel send before its write deadline expires it
+	adapter.SetWriteDeadline(time.Now().Add(300 * time.Millisecond))
+
+	blocked := make(chan struct{})
+
+	go func() {
+		defer close(blocked)
+		adapter.setData(ctx, map[string]interface{}{"Signal.Test.value": 1000}) //nolint:errcheck
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		if _, err := adapter.getData(ctx, []string{"Signal.Test.value"}); err != nil {
+			t.Errorf("GetData should not fail while a setData call is blocked: %s", err)
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("getData was blocked by a setData call stuck sending to a full subscribeChannel")
+	}
+
+	select {
+	case <-blocked:
+	case <-time.After(time.Second):
+		t.Fatal("blocked setData call never returned after its write deadline elapsed")
+	}
+}
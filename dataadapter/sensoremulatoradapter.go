@@ -2,6 +2,7 @@ package dataadapter
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -26,13 +27,21 @@ const (
 type SensorEmulatorAdapter struct {
 	sensorURL    *url.URL
 	updatePeriod uint64
+	transport    string
 
 	baseAdapter *BaseAdapter
+
+	cancel context.CancelFunc
+	done   chan struct{}
 }
 
 type config struct {
 	SensorURL    string
 	UpdatePeriod uint64
+	// Transport selects how the adapter receives sensor updates: "poll"
+	// (default) periodically fetches /stats, "sse" and "websocket" open a
+	// persistent push stream instead
+	Transport string
 }
 
 /*******************************************************************************
@@ -45,7 +54,7 @@ func NewSensorEmulatorAdapter(configJSON []byte) (adapter *SensorEmulatorAdapter
 
 	adapter = new(SensorEmulatorAdapter)
 
-	cfg := config{UpdatePeriod: defaultUpdatePeriod}
+	cfg := config{UpdatePeriod: defaultUpdatePeriod, Transport: transportPoll}
 
 	// Parse config
 	err = json.Unmarshal(configJSON, &cfg)
@@ -57,7 +66,15 @@ func NewSensorEmulatorAdapter(configJSON []byte) (adapter *SensorEmulatorAdapter
 		return nil, errors.New("Sensor URL should be defined")
 	}
 
+	switch cfg.Transport {
+	case transportPoll, transportSSE, transportWebSocket:
+
+	default:
+		return nil, fmt.Errorf("unknown transport: %s", cfg.Transport)
+	}
+
 	adapter.updatePeriod = cfg.UpdatePeriod
+	adapter.transport = cfg.Transport
 	adapter.sensorURL, err = url.Parse(cfg.SensorURL)
 
 	if adapter.baseAdapter, err = newBaseAdapter(); err != nil {
@@ -67,7 +84,7 @@ func NewSensorEmulatorAdapter(configJSON []byte) (adapter *SensorEmulatorAdapter
 	adapter.baseAdapter.name = "SensorEmulatorAdapter"
 
 	// Create data map
-	data, err := adapter.getDataFromSensorEmulator()
+	data, err := adapter.getDataFromSensorEmulator(context.Background())
 	if err != nil {
 		return nil, err
 	}
@@ -84,11 +101,27 @@ func NewSensorEmulatorAdapter(configJSON []byte) (adapter *SensorEmulatorAdapter
 	adapter.baseAdapter.data["Attribute.Emulator.stop"] = &baseData{}
 	adapter.baseAdapter.data["Attribute.Emulator.tire_break"] = &baseData{}
 
-	go adapter.processData()
+	ctx, cancel := context.WithCancel(context.Background())
+	adapter.cancel = cancel
+	adapter.done = make(chan struct{})
+
+	go func() {
+		defer close(adapter.done)
+		adapter.run(ctx)
+	}()
 
 	return adapter, nil
 }
 
+// Close stops the adapter's background poller or push-stream goroutine and
+// waits for it to exit
+func (adapter *SensorEmulatorAdapter) Close() (err error) {
+	adapter.cancel()
+	<-adapter.done
+
+	return nil
+}
+
 /*******************************************************************************
  * Public
  ******************************************************************************/
@@ -114,12 +147,13 @@ func (adapter *SensorEmulatorAdapter) IsPathPublic(path string) (result bool, er
 }
 
 // GetData returns data by path
-func (adapter *SensorEmulatorAdapter) GetData(pathList []string) (data map[string]interface{}, err error) {
-	return adapter.baseAdapter.getData(pathList)
+func (adapter *SensorEmulatorAdapter) GetData(
+	ctx context.Context, pathList []string) (data map[string]interface{}, err error) {
+	return adapter.baseAdapter.getData(ctx, pathList)
 }
 
 // SetData sets data by pathes
-func (adapter *SensorEmulatorAdapter) SetData(data map[string]interface{}) (err error) {
+func (adapter *SensorEmulatorAdapter) SetData(ctx context.Context, data map[string]interface{}) (err error) {
 	sendData, err := convertVisFormatToData(data)
 	if err != nil {
 		return err
@@ -134,7 +168,13 @@ func (adapter *SensorEmulatorAdapter) SetData(data map[string]interface{}) (err
 
 	log.WithField("url", address).Debugf("Set data to sensor emulator: %s", string(sendData))
 
-	res, err := http.Post(address, "application/json", bytes.NewReader(sendData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, address, bytes.NewReader(sendData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -142,7 +182,7 @@ func (adapter *SensorEmulatorAdapter) SetData(data map[string]interface{}) (err
 		return errors.New(res.Status)
 	}
 
-	return adapter.baseAdapter.setData(data)
+	return adapter.baseAdapter.setData(ctx, data)
 }
 
 // GetSubscribeChannel returns channel on which data changes will be sent
@@ -151,20 +191,53 @@ func (adapter *SensorEmulatorAdapter) GetSubscribeChannel() (channel <-chan map[
 }
 
 // Subscribe subscribes for data changes
-func (adapter *SensorEmulatorAdapter) Subscribe(pathList []string) (err error) {
-	return adapter.baseAdapter.subscribe(pathList)
+func (adapter *SensorEmulatorAdapter) Subscribe(ctx context.Context, pathList []string) (err error) {
+	return adapter.baseAdapter.subscribe(ctx, pathList)
 }
 
-// Unsubscribe unsubscribes from data changes
+// SubscribeFiltered subscribes for data changes matching a filter expression,
+// e.g. "Signal.Emulator.Vehicle.Speed > 30", so only matching events are sent
+// on the subscribe channel
+func (adapter *SensorEmulatorAdapter) SubscribeFiltered(pathList []string, expr string) (
+	subscriptionID string, err error) {
+	return adapter.baseAdapter.subscribeFiltered(pathList, expr)
+}
+
+// Unsubscribe removes every subscription - filtered or not - registered on
+// exactly pathList
 func (adapter *SensorEmulatorAdapter) Unsubscribe(pathList []string) (err error) {
 	return adapter.baseAdapter.unsubscribe(pathList)
 }
 
+// UnsubscribeID removes the single subscription identified by
+// subscriptionID, e.g. one returned by SubscribeFiltered, without touching
+// any other subscription sharing the same path list
+func (adapter *SensorEmulatorAdapter) UnsubscribeID(subscriptionID string) (err error) {
+	return adapter.baseAdapter.unsubscribeID(subscriptionID)
+}
+
 // UnsubscribeAll unsubscribes from all data changes
 func (adapter *SensorEmulatorAdapter) UnsubscribeAll() (err error) {
 	return adapter.baseAdapter.unsubscribeAll()
 }
 
+// Capabilities returns the adapter's capabilities. Every Attribute.Emulator.*
+// path is the write-list convertVisFormatToData accepts from VIS clients;
+// every Signal.Emulator.* path comes from the sensor emulator's /stats
+// snapshot and is read-only.
+func (adapter *SensorEmulatorAdapter) Capabilities() (capabilities AdapterCapabilities) {
+	capabilities = adapter.baseAdapter.capabilities()
+
+	for path, pathCaps := range capabilities.Paths {
+		if strings.HasPrefix(path, "Attribute.Emulator.") {
+			pathCaps.Writable = true
+			capabilities.Paths[path] = pathCaps
+		}
+	}
+
+	return capabilities
+}
+
 /*******************************************************************************
  * Private
  ******************************************************************************/
@@ -199,7 +272,8 @@ func convertDataToVisFormat(dataJSON []byte) (visData map[string]interface{}, er
 	return visData, nil
 }
 
-func (adapter *SensorEmulatorAdapter) getDataFromSensorEmulator() (visData map[string]interface{}, err error) {
+func (adapter *SensorEmulatorAdapter) getDataFromSensorEmulator(
+	ctx context.Context) (visData map[string]interface{}, err error) {
 	path, err := url.Parse("stats")
 	if err != nil {
 		return visData, err
@@ -207,7 +281,12 @@ func (adapter *SensorEmulatorAdapter) getDataFromSensorEmulator() (visData map[s
 
 	address := adapter.sensorURL.ResolveReference(path).String()
 
-	res, err := http.Get(address)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return visData, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return visData, err
 	}
@@ -223,17 +302,33 @@ func (adapter *SensorEmulatorAdapter) getDataFromSensorEmulator() (visData map[s
 	return convertDataToVisFormat(data)
 }
 
-func (adapter *SensorEmulatorAdapter) processData() {
+// processData polls the sensor emulator every updatePeriod until ctx is
+// canceled, so Close can abort an in-flight poll cleanly instead of leaking
+// this goroutine
+func (adapter *SensorEmulatorAdapter) processData(ctx context.Context) {
 	ticker := time.NewTicker(time.Duration(adapter.updatePeriod) * time.Millisecond)
+	defer ticker.Stop()
+
 	for {
+		// checked on its own first so a ticker tick arriving at the same
+		// instant as cancellation can't win the race against ctx.Done()
 		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+
 		case <-ticker.C:
-			data, err := adapter.getDataFromSensorEmulator()
+			data, err := adapter.getDataFromSensorEmulator(ctx)
 			if err != nil {
 				log.Errorf("Can't read data: %s", err)
 				continue
 			}
-			if err = adapter.baseAdapter.setData(data); err != nil {
+			if err = adapter.baseAdapter.setData(ctx, data); err != nil {
 				log.Errorf("Can't update data: %s", err)
 				continue
 			}
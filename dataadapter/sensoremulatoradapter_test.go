@@ -0,0 +1,106 @@
+package dataadapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aos_vis/dataadapter"
+)
+
+/*******************************************************************************
+ * Tests
+ ******************************************************************************/
+
+func TestUnsubscribeIDLeavesOtherSubscriptionsIntact(t *testing.T) {
+	var value int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"value": %d}`, atomic.AddInt32(&value, 1))
+	}))
+	defer server.Close()
+
+	configJSON, err := json.Marshal(map[string]interface{}{
+		"SensorURL":    server.URL,
+		"UpdatePeriod": 20,
+	})
+	if err != nil {
+		t.Fatalf("Can't marshal config: %s", err)
+	}
+
+	adapter, err := dataadapter.NewSensorEmulatorAdapter(configJSON)
+	if err != nil {
+		t.Fatalf("Can't create adapter: %s", err)
+	}
+
+	ctx := context.Background()
+
+	if err = adapter.Subscribe(ctx, []string{"Signal.Emulator.value"}); err != nil {
+		t.Fatalf("Can't subscribe: %s", err)
+	}
+
+	subscriptionID, err := adapter.SubscribeFiltered([]string{"Signal.Emulator.value"}, "Signal.Emulator.value > 0")
+	if err != nil {
+		t.Fatalf("Can't subscribe filtered: %s", err)
+	}
+
+	if err = adapter.UnsubscribeID(subscriptionID); err != nil {
+		t.Fatalf("Can't unsubscribe by id: %s", err)
+	}
+
+	// only the plain Subscribe should still be registered; UnsubscribeID must
+	// not have dropped it just because it shares Signal.Emulator.value's
+	// path list with the filtered subscription that was removed
+	select {
+	case <-adapter.GetSubscribeChannel():
+
+	case <-time.After(time.Second):
+		t.Fatal("Plain subscription should still receive changes after UnsubscribeID removed the filtered one")
+	}
+}
+
+func TestCloseStopsPoller(t *testing.T) {
+	var pollCount int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&pollCount, 1)
+		fmt.Fprint(w, `{"value": 0}`)
+	}))
+	defer server.Close()
+
+	configJSON, err := json.Marshal(map[string]interface{}{
+		"SensorURL":    server.URL,
+		"UpdatePeriod": 10,
+	})
+	if err != nil {
+		t.Fatalf("Can't marshal config: %s", err)
+	}
+
+	adapter, err := dataadapter.NewSensorEmulatorAdapter(configJSON)
+	if err != nil {
+		t.Fatalf("Can't create adapter: %s", err)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	if err = adapter.Close(); err != nil {
+		t.Fatalf("Can't close adapter: %s", err)
+	}
+
+	// a poll already in flight when Close was called may still land on the
+	// server even though the client gave up on it; give it a moment to settle
+	// before taking the baseline
+	time.Sleep(20 * time.Millisecond)
+	countAtClose := atomic.LoadInt32(&pollCount)
+
+	time.Sleep(100 * time.Millisecond)
+
+	if atomic.LoadInt32(&pollCount) != countAtClose {
+		t.Error("Poller kept running after Close")
+	}
+}
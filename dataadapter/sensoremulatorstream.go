@@ -0,0 +1,295 @@
+package dataadapter
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	log "github.com/sirupsen/logrus"
+)
+
+/*******************************************************************************
+ * Consts
+ ******************************************************************************/
+
+const (
+	transportPoll      = "poll"
+	transportSSE       = "sse"
+	transportWebSocket = "websocket"
+)
+
+const (
+	minReconnectBackoff = 100 * time.Millisecond
+	maxReconnectBackoff = 30 * time.Second
+	streamIdleTimeout   = 60 * time.Second
+)
+
+/*******************************************************************************
+ * Vars
+ ******************************************************************************/
+
+// errUnsupportedTransport is returned by a stream dialer when the sensor
+// emulator answers the upgrade request with 404/405, signalling that the
+// adapter should give up on the push transport and fall back to polling
+var errUnsupportedTransport = errors.New("dataadapter: sensor emulator doesn't support push transport")
+
+/*******************************************************************************
+ * Private
+ ******************************************************************************/
+
+// run starts the adapter's data source: a push stream for transportSSE and
+// transportWebSocket, or the legacy ticker-driven poller otherwise. It
+// returns once ctx is canceled.
+func (adapter *SensorEmulatorAdapter) run(ctx context.Context) {
+	switch adapter.transport {
+	case transportSSE:
+		adapter.runPushTransport(ctx, adapter.streamSSE)
+
+	case transportWebSocket:
+		adapter.runPushTransport(ctx, adapter.streamWebSocket)
+
+	default:
+		adapter.processData(ctx)
+	}
+}
+
+// runPushTransport keeps a persistent stream open, reconnecting with
+// exponential backoff (minReconnectBackoff up to maxReconnectBackoff)
+// whenever the connection drops. If the sensor emulator doesn't support the
+// requested upgrade it falls back to polling for the remaining lifetime of
+// the adapter. It returns as soon as ctx is canceled instead of continuing
+// to reconnect.
+func (adapter *SensorEmulatorAdapter) runPushTransport(ctx context.Context, stream func(ctx context.Context) error) {
+	backoff := minReconnectBackoff
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		err := stream(ctx)
+		if err == nil {
+			continue
+		}
+
+		if errors.Is(err, errUnsupportedTransport) {
+			log.Warnf("Sensor emulator doesn't support %s transport, falling back to polling", adapter.transport)
+			adapter.processData(ctx)
+
+			return
+		}
+
+		log.Errorf("Stream connection lost, reconnecting in %s: %s", backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return
+		}
+
+		if backoff *= 2; backoff > maxReconnectBackoff {
+			backoff = maxReconnectBackoff
+		}
+	}
+}
+
+// streamSSE opens a persistent Server-Sent Events connection to the sensor
+// emulator and applies each "data:" frame as a delta. It returns
+// errUnsupportedTransport if the emulator doesn't implement SSE on this
+// endpoint, and a nil error on a clean EOF so the caller reconnects.
+func (adapter *SensorEmulatorAdapter) streamSSE(ctx context.Context) (err error) {
+	path, err := url.Parse("stats")
+	if err != nil {
+		return err
+	}
+
+	address := adapter.sensorURL.ResolveReference(path).String()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, address, nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Accept", "text/event-stream")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed {
+		return errUnsupportedTransport
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected SSE status: %s", res.Status)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	activity := make(chan struct{}, 1)
+	go watchIdle(streamCtx, cancel, streamIdleTimeout, activity)
+	go closeOnDone(streamCtx, res.Body)
+
+	var frame strings.Builder
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		notifyActivity(activity)
+
+		line := scanner.Text()
+
+		if line == "" {
+			if frame.Len() == 0 {
+				continue
+			}
+
+			if err = adapter.applyDelta(streamCtx, []byte(frame.String())); err != nil {
+				return err
+			}
+
+			frame.Reset()
+
+			continue
+		}
+
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue // ignore event:/id:/comment lines
+		}
+
+		if frame.Len() > 0 {
+			frame.WriteByte('\n')
+		}
+
+		frame.WriteString(strings.TrimPrefix(data, " "))
+	}
+
+	return scanner.Err()
+}
+
+// streamWebSocket opens a persistent websocket connection to the sensor
+// emulator. The first message is an initial snapshot, subsequent messages
+// are patches; both are applied through baseAdapter.setData, which merges
+// whichever paths are present. It returns errUnsupportedTransport if the
+// emulator doesn't implement the upgrade on this endpoint.
+func (adapter *SensorEmulatorAdapter) streamWebSocket(ctx context.Context) (err error) {
+	path, err := url.Parse("stats")
+	if err != nil {
+		return err
+	}
+
+	wsURL := *adapter.sensorURL.ResolveReference(path)
+
+	wsURL.Scheme = "ws"
+	if adapter.sensorURL.Scheme == "https" {
+		wsURL.Scheme = "wss"
+	}
+
+	conn, res, err := websocket.DefaultDialer.DialContext(ctx, wsURL.String(), nil)
+	if err != nil {
+		if res != nil && (res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusMethodNotAllowed) {
+			return errUnsupportedTransport
+		}
+
+		return err
+	}
+	defer conn.Close()
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	activity := make(chan struct{}, 1)
+	go watchIdle(streamCtx, cancel, streamIdleTimeout, activity)
+	go closeOnDone(streamCtx, conn)
+
+	conn.SetPongHandler(func(string) error {
+		notifyActivity(activity)
+		return nil
+	})
+
+	go sendPings(streamCtx, conn, streamIdleTimeout/3)
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		notifyActivity(activity)
+
+		if err = adapter.applyDelta(streamCtx, message); err != nil {
+			return err
+		}
+	}
+}
+
+func (adapter *SensorEmulatorAdapter) applyDelta(ctx context.Context, frame []byte) (err error) {
+	visData, err := convertDataToVisFormat(frame)
+	if err != nil {
+		return err
+	}
+
+	return adapter.baseAdapter.setData(ctx, visData)
+}
+
+// watchIdle cancels cancel once timeout passes without a tick on activity
+func watchIdle(ctx context.Context, cancel context.CancelFunc, timeout time.Duration, activity <-chan struct{}) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-activity:
+			if !timer.Stop() {
+				<-timer.C
+			}
+
+			timer.Reset(timeout)
+
+		case <-timer.C:
+			cancel()
+			return
+		}
+	}
+}
+
+func notifyActivity(activity chan<- struct{}) {
+	select {
+	case activity <- struct{}{}:
+	default:
+	}
+}
+
+func closeOnDone(ctx context.Context, closer interface{ Close() error }) {
+	<-ctx.Done()
+	closer.Close()
+}
+
+func sendPings(ctx context.Context, conn *websocket.Conn, period time.Duration) {
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case <-ticker.C:
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
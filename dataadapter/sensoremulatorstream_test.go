@@ -0,0 +1,156 @@
+package dataadapter_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"aos_vis/dataadapter"
+)
+
+/*******************************************************************************
+ * Tests
+ ******************************************************************************/
+
+func TestSSETransportAppliesFramesInOrder(t *testing.T) {
+	frames := []string{`{"value": 1}`, `{"value": 2}`, `{"value": 3}`}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			fmt.Fprint(w, `{"value": 0}`)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.WriteHeader(http.StatusOK)
+
+		flusher, _ := w.(http.Flusher)
+
+		for _, frame := range frames {
+			fmt.Fprintf(w, "data: %s\n\n", frame)
+
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}))
+	defer server.Close()
+
+	configJSON, err := json.Marshal(map[string]interface{}{
+		"SensorURL": server.URL,
+		"Transport": "sse",
+	})
+	if err != nil {
+		t.Fatalf("Can't marshal config: %s", err)
+	}
+
+	adapter, err := dataadapter.NewSensorEmulatorAdapter(configJSON)
+	if err != nil {
+		t.Fatalf("Can't create adapter: %s", err)
+	}
+
+	if err = adapter.Subscribe(context.Background(), []string{"Signal.Emulator.value"}); err != nil {
+		t.Fatalf("Can't subscribe: %s", err)
+	}
+
+	channel := adapter.GetSubscribeChannel()
+
+	for _, expected := range []float64{1, 2, 3} {
+		select {
+		case changes := <-channel:
+			value, ok := changes["Signal.Emulator.value"]
+			if !ok {
+				t.Fatalf("Missing expected path in changes: %v", changes)
+			}
+
+			if value != expected {
+				t.Errorf("Unexpected value: got %v, want %v", value, expected)
+			}
+
+		case <-time.After(2 * time.Second):
+			t.Fatal("Timeout waiting for subscribe event")
+		}
+	}
+}
+
+func TestSSETransportFallsBackToPollingWhenUnsupported(t *testing.T) {
+	var pollCount int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		atomic.AddInt32(&pollCount, 1)
+
+		fmt.Fprint(w, `{"value": 0}`)
+	}))
+	defer server.Close()
+
+	configJSON, err := json.Marshal(map[string]interface{}{
+		"SensorURL":    server.URL,
+		"Transport":    "sse",
+		"UpdatePeriod": 20,
+	})
+	if err != nil {
+		t.Fatalf("Can't marshal config: %s", err)
+	}
+
+	if _, err = dataadapter.NewSensorEmulatorAdapter(configJSON); err != nil {
+		t.Fatalf("Can't create adapter: %s", err)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	if count := atomic.LoadInt32(&pollCount); count < 2 {
+		t.Errorf("Expected adapter to fall back to polling, got %d poll requests", count)
+	}
+}
+
+func TestCloseStopsReconnectLoop(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") == "text/event-stream" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		fmt.Fprint(w, `{"value": 0}`)
+	}))
+
+	configJSON, err := json.Marshal(map[string]interface{}{
+		"SensorURL": server.URL,
+		"Transport": "sse",
+	})
+	if err != nil {
+		t.Fatalf("Can't marshal config: %s", err)
+	}
+
+	adapter, err := dataadapter.NewSensorEmulatorAdapter(configJSON)
+	if err != nil {
+		t.Fatalf("Can't create adapter: %s", err)
+	}
+
+	// let the reconnect loop start spinning with the server refusing every
+	// attempt, then pull the server out from under it
+	time.Sleep(20 * time.Millisecond)
+	server.Close()
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		adapter.Close() //nolint:errcheck
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close did not return - reconnect loop is still spinning")
+	}
+}
@@ -0,0 +1,224 @@
+// Package filter implements parsing and evaluation of W3C VIS filter
+// expressions used to throttle subscription events on the server side.
+package filter
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+/*******************************************************************************
+ * Types
+ ******************************************************************************/
+
+// Operation filter term comparison operation
+type Operation int
+
+// Filter term operations
+const (
+	EQ Operation = iota
+	NE
+	GT
+	LT
+	GE
+	LE
+	RE
+)
+
+// FilterTerm single `<path><op><value>` comparison
+type FilterTerm struct {
+	Path  string
+	Op    Operation
+	Value string
+
+	re *regexp.Regexp
+}
+
+// Filter compiled filter expression: OR of AND groups of terms
+type Filter struct {
+	groups [][]*FilterTerm
+}
+
+/*******************************************************************************
+ * Private vars
+ ******************************************************************************/
+
+var termRegexp = regexp.MustCompile(`^\s*([A-Za-z0-9_.]+)\s*(==|!=|>=|<=|>|<|~)\s*(.+?)\s*$`)
+
+var operationByToken = map[string]Operation{
+	"==": EQ,
+	"!=": NE,
+	">":  GT,
+	"<":  LT,
+	">=": GE,
+	"<=": LE,
+	"~":  RE,
+}
+
+/*******************************************************************************
+ * Public
+ ******************************************************************************/
+
+// New parses a filter expression into a Filter ready for evaluation.
+// Terms are joined with `&&`/`||`, e.g.:
+//
+//	Signal.Emulator.Vehicle.Speed > 30
+//	Signal.Emulator.Vehicle.CabinTemperature <= 20 && Signal.Emulator.Vehicle.Doors ~ "Open"
+func New(expr string) (filter *Filter, err error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty filter expression")
+	}
+
+	filter = &Filter{}
+
+	for _, orPart := range strings.Split(expr, "||") {
+		var group []*FilterTerm
+
+		for _, andPart := range strings.Split(orPart, "&&") {
+			term, err := parseTerm(andPart)
+			if err != nil {
+				return nil, err
+			}
+
+			group = append(group, term)
+		}
+
+		filter.groups = append(filter.groups, group)
+	}
+
+	return filter, nil
+}
+
+// Match evaluates the filter against a data snapshot. The snapshot is the
+// full set of currently known path/value pairs, not just the changed ones,
+// so terms may reference paths other than the one being subscribed to.
+func (filter *Filter) Match(snapshot map[string]interface{}) (result bool) {
+	for _, group := range filter.groups {
+		groupMatched := true
+
+		for _, term := range group {
+			if !term.match(snapshot) {
+				groupMatched = false
+				break
+			}
+		}
+
+		if groupMatched {
+			return true
+		}
+	}
+
+	return false
+}
+
+/*******************************************************************************
+ * Private
+ ******************************************************************************/
+
+func parseTerm(s string) (term *FilterTerm, err error) {
+	matches := termRegexp.FindStringSubmatch(s)
+	if matches == nil {
+		return nil, fmt.Errorf("can't parse filter term: %s", strings.TrimSpace(s))
+	}
+
+	op, ok := operationByToken[matches[2]]
+	if !ok {
+		return nil, fmt.Errorf("unknown filter operation: %s", matches[2])
+	}
+
+	term = &FilterTerm{Path: matches[1], Op: op, Value: unquote(matches[3])}
+
+	if op == RE {
+		if term.re, err = regexp.Compile(term.Value); err != nil {
+			return nil, fmt.Errorf("can't compile filter regexp %s: %s", term.Value, err)
+		}
+	}
+
+	return term, nil
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
+
+func (term *FilterTerm) match(snapshot map[string]interface{}) (result bool) {
+	value, ok := snapshot[term.Path]
+	if !ok {
+		return false
+	}
+
+	if term.Op == RE {
+		return term.re.MatchString(fmt.Sprintf("%v", value))
+	}
+
+	if lhs, rhs, ok := asFloats(value, term.Value); ok {
+		return compareFloats(lhs, term.Op, rhs)
+	}
+
+	return compareStrings(fmt.Sprintf("%v", value), term.Op, term.Value)
+}
+
+func asFloats(value interface{}, literal string) (lhs float64, rhs float64, ok bool) {
+	rhs, err := strconv.ParseFloat(literal, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	v := reflect.ValueOf(value)
+
+	switch {
+	case v.Kind() >= reflect.Int && v.Kind() <= reflect.Int64:
+		return float64(v.Int()), rhs, true
+	case v.Kind() >= reflect.Uint && v.Kind() <= reflect.Uint64:
+		return float64(v.Uint()), rhs, true
+	case v.Kind() == reflect.Float32 || v.Kind() == reflect.Float64:
+		return v.Float(), rhs, true
+	default:
+		return 0, 0, false
+	}
+}
+
+func compareFloats(lhs float64, op Operation, rhs float64) (result bool) {
+	switch op {
+	case EQ:
+		return lhs == rhs
+	case NE:
+		return lhs != rhs
+	case GT:
+		return lhs > rhs
+	case LT:
+		return lhs < rhs
+	case GE:
+		return lhs >= rhs
+	case LE:
+		return lhs <= rhs
+	default:
+		return false
+	}
+}
+
+func compareStrings(lhs string, op Operation, rhs string) (result bool) {
+	switch op {
+	case EQ:
+		return lhs == rhs
+	case NE:
+		return lhs != rhs
+	case GT:
+		return lhs > rhs
+	case LT:
+		return lhs < rhs
+	case GE:
+		return lhs >= rhs
+	case LE:
+		return lhs <= rhs
+	default:
+		return false
+	}
+}
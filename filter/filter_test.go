@@ -0,0 +1,92 @@
+package filter_test
+
+import (
+	"testing"
+
+	"aos_vis/filter"
+)
+
+/*******************************************************************************
+ * Tests
+ ******************************************************************************/
+
+func TestNumericThreshold(t *testing.T) {
+	f, err := filter.New("Signal.Emulator.Vehicle.Speed > 30")
+	if err != nil {
+		t.Fatalf("Can't parse filter: %s", err)
+	}
+
+	if f.Match(map[string]interface{}{"Signal.Emulator.Vehicle.Speed": 29}) {
+		t.Error("Filter should not match value below threshold")
+	}
+
+	if !f.Match(map[string]interface{}{"Signal.Emulator.Vehicle.Speed": 31}) {
+		t.Error("Filter should match value above threshold")
+	}
+}
+
+func TestAndOr(t *testing.T) {
+	f, err := filter.New(
+		`Signal.Emulator.Vehicle.CabinTemperature <= 20 && Signal.Emulator.Vehicle.Doors ~ "Open"`)
+	if err != nil {
+		t.Fatalf("Can't parse filter: %s", err)
+	}
+
+	data := map[string]interface{}{
+		"Signal.Emulator.Vehicle.CabinTemperature": 18,
+		"Signal.Emulator.Vehicle.Doors":            "FrontLeftOpen",
+	}
+
+	if !f.Match(data) {
+		t.Error("Filter should match when both terms match")
+	}
+
+	data["Signal.Emulator.Vehicle.CabinTemperature"] = 25
+
+	if f.Match(data) {
+		t.Error("Filter should not match when one term fails")
+	}
+
+	orFilter, err := filter.New("Signal.Emulator.Vehicle.Speed > 100 || Signal.Emulator.Vehicle.Speed < 10")
+	if err != nil {
+		t.Fatalf("Can't parse filter: %s", err)
+	}
+
+	if !orFilter.Match(map[string]interface{}{"Signal.Emulator.Vehicle.Speed": 5}) {
+		t.Error("Filter should match when one OR group matches")
+	}
+
+	if orFilter.Match(map[string]interface{}{"Signal.Emulator.Vehicle.Speed": 50}) {
+		t.Error("Filter should not match when no OR group matches")
+	}
+}
+
+func TestRegexp(t *testing.T) {
+	f, err := filter.New(`Signal.Emulator.Vehicle.Doors ~ "^Open.*"`)
+	if err != nil {
+		t.Fatalf("Can't parse filter: %s", err)
+	}
+
+	if !f.Match(map[string]interface{}{"Signal.Emulator.Vehicle.Doors": "OpenFront"}) {
+		t.Error("Filter should match regexp")
+	}
+
+	if f.Match(map[string]interface{}{"Signal.Emulator.Vehicle.Doors": "ClosedFront"}) {
+		t.Error("Filter should not match regexp")
+	}
+}
+
+func TestMalformedExpression(t *testing.T) {
+	malformedExpressions := []string{
+		"",
+		"Signal.Emulator.Vehicle.Speed",
+		"Signal.Emulator.Vehicle.Speed ?? 30",
+		`Signal.Emulator.Vehicle.Doors ~ "["`,
+	}
+
+	for _, expr := range malformedExpressions {
+		if _, err := filter.New(expr); err == nil {
+			t.Errorf("Expected parse error for expression: %s", expr)
+		}
+	}
+}